@@ -7,6 +7,9 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -16,18 +19,38 @@ import (
 )
 
 type akaros struct {
-	ignores []*regexp.Regexp
-	objfile string
+	ignores     []*regexp.Regexp
+	kernelSrc   string
+	objfile     string
+	userBinDirs []string
 }
 
 func ctorAkaros(kernelSrc, kernelObj string, ignores []*regexp.Regexp) (Reporter, []string, error) {
 	ctx := &akaros{
-		ignores: ignores,
-		objfile: filepath.Join(kernelObj, "akaros-kernel-64b"),
+		ignores:   ignores,
+		kernelSrc: kernelSrc,
+		objfile:   filepath.Join(kernelObj, "akaros-kernel-64b"),
+		userBinDirs: []string{
+			filepath.Join(kernelObj, "kern", "kfs", "bin"),
+			filepath.Join(kernelObj, "tests"),
+		},
 	}
 	return ctx, nil, nil
 }
 
+func (ctx *akaros) findBinary(proc string) string {
+	if proc == "" {
+		return ctx.objfile
+	}
+	for _, dir := range ctx.userBinDirs {
+		path := filepath.Join(dir, proc)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ctx.objfile
+}
+
 func (ctx *akaros) ContainsCrash(output []byte) bool {
 	return containsCrash(output, akarosOopses, ctx.ignores)
 }
@@ -73,49 +96,72 @@ func (ctx *akaros) Symbolize(rep *Report) error {
 	symb := symbolizer.NewSymbolizer()
 	defer symb.Close()
 	var symbolized []byte
+	var guiltyFile string
+	objfile := ctx.objfile
 	s := bufio.NewScanner(bytes.NewReader(rep.Report))
 	for s.Scan() {
 		line := bytes.Trim(s.Bytes(), "\r")
-		line = ctx.symbolizeLine(symb.Symbolize, ctx.objfile, line)
-		symbolized = append(symbolized, line...)
+		// Each backtrace header says whether the frames that follow are kernel or per-process.
+		if match := akarosBacktraceRe.FindSubmatch(line); match != nil {
+			objfile = ctx.findBinary(string(match[1]))
+		}
+		symLine, file, fn := ctx.symbolizeLine(symb.Symbolize, objfile, line)
+		if guiltyFile == "" && file != "" && !isSkippedFrameFunc(fn) {
+			guiltyFile = file
+		}
+		symbolized = append(symbolized, symLine...)
 		symbolized = append(symbolized, '\n')
 	}
 	rep.Report = symbolized
+	if guiltyFile != "" {
+		if maintainers, err := ctx.getMaintainers(guiltyFile); err == nil {
+			rep.Maintainers = maintainers
+		}
+	}
 	return nil
 }
 
 func (ctx *akaros) symbolizeLine(symbFunc func(bin string, pc uint64) ([]symbolizer.Frame, error),
-	objfile string, line []byte) []byte {
+	objfile string, line []byte) (symbolized []byte, file string, fn string) {
 	match := akarosSymbolizeRe.FindSubmatchIndex(line)
 	if match == nil {
-		return line
+		return line, "", ""
 	}
+	fn = string(line[match[4]:match[5]])
 	addr, err := strconv.ParseUint(string(line[match[2]:match[3]]), 0, 64)
 	if err != nil {
-		return line
+		return line, "", fn
 	}
 	frames, err := symbFunc(objfile, addr-1)
 	if err != nil || len(frames) == 0 {
-		return line
+		return line, "", fn
 	}
-	var symbolized []byte
 	for i, frame := range frames {
 		if i != 0 {
 			symbolized = append(symbolized, '\n')
 		}
-		file := frame.File
-		if pos := strings.LastIndex(file, "/kern/"); pos != -1 {
-			file = file[pos+6:]
+		frameFile := frame.File
+		if pos := strings.LastIndex(frameFile, "/kern/"); pos != -1 {
+			frameFile = frameFile[pos+6:]
 		}
 		modified := append([]byte{}, line...)
-		modified = append(modified, fmt.Sprintf(" at %v:%v", file, frame.Line)...)
+		modified = append(modified, fmt.Sprintf(" at %v:%v", frameFile, frame.Line)...)
 		if frame.Inline {
 			modified = replace(modified, match[4], match[5], []byte(frame.Func))
 			modified = replace(modified, match[2], match[3], []byte("     [inline]     "))
 		}
 		symbolized = append(symbolized, modified...)
 	}
-	return symbolized
+	return symbolized, frames[0].File, fn
+}
+
+func isSkippedFrameFunc(fn string) bool {
+	for _, pattern := range akarosStackParams.skipPatterns {
+		if strings.Contains(fn, pattern) {
+			return true
+		}
+	}
+	return false
 }
 
 func (ctx *akaros) minimizeReport(report []byte) []byte {
@@ -133,9 +179,92 @@ func (ctx *akaros) minimizeReport(report []byte) []byte {
 	return out.Bytes()
 }
 
+func (ctx *akaros) getMaintainers(file string) ([]string, error) {
+	if ctx.kernelSrc == "" {
+		return nil, nil
+	}
+	if pos := strings.LastIndex(file, "/kern/"); pos != -1 {
+		file = file[pos+1:]
+	}
+	file = strings.TrimPrefix(file, "/")
+	var maintainers []string
+	maintainers = append(maintainers, ctx.maintainersFromFile(file)...)
+	maintainers = append(maintainers, ctx.maintainersFromGit(file)...)
+	return uniqueStrings(maintainers), nil
+}
+
+func (ctx *akaros) maintainersFromFile(file string) []string {
+	kernelSrc := filepath.Clean(ctx.kernelSrc)
+	dir := filepath.Dir(filepath.Join(kernelSrc, file))
+	for {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "MAINTAINERS"))
+		if err == nil {
+			return parseMaintainersFile(data)
+		}
+		if dir == kernelSrc {
+			return nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir || !strings.HasPrefix(parent, kernelSrc) {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+func (ctx *akaros) maintainersFromGit(file string) []string {
+	cmd := exec.Command("git", "log", "--format=%ae", "-n", "10", "--", file)
+	cmd.Dir = ctx.kernelSrc
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var emails []string
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) != 0 {
+			emails = append(emails, string(line))
+		}
+	}
+	return emails
+}
+
+func parseMaintainersFile(data []byte) []string {
+	var maintainers []string
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if !bytes.HasPrefix(line, []byte("M:")) {
+			continue
+		}
+		if match := maintainerEmailRe.FindSubmatch(line); match != nil {
+			maintainers = append(maintainers, string(match[1]))
+		}
+	}
+	return maintainers
+}
+
+func uniqueStrings(list []string) []string {
+	seen := make(map[string]bool)
+	var res []string
+	for _, s := range list {
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		res = append(res, s)
+	}
+	return res
+}
+
 var (
 	akarosSymbolizeRe = compile(`^#[0-9]+ \[\<(0x[0-9a-f]+)\>\] in ([a-zA-Z0-9_]+)`)
-	akarosBacktraceRe = compile(`(?:Stack Backtrace|Backtrace of kernel context) on Core [0-9]+:`)
+	// Process group is empty for kernel backtraces, set for per-process ones.
+	akarosBacktraceRe = compile(`(?:Stack Backtrace(?: for process ([a-zA-Z0-9_]+))?|Backtrace of kernel context) on Core [0-9]+:`)
+	maintainerEmailRe = compile(`<([^>]+)>`)
+
+	akarosProcKilledRe    = compile(`proc ([a-zA-Z0-9_]+) \(pid [0-9]+\) was killed due to (.*)`)
+	akarosUserTrapRe      = compile(`unhandled user trap [0-9]+ for process ([a-zA-Z0-9_]+) \(pid [0-9]+\): (.*)`)
+	akarosUserPageFaultRe = compile(`#PF in user context at 0x[0-9a-f]+, from process ([a-zA-Z0-9_]+) \(pid [0-9]+\)`)
 )
 
 var akarosStackParams = &stackParams{
@@ -186,4 +315,52 @@ var akarosOopses = []*oops{
 		},
 		[]*regexp.Regexp{},
 	},
+	&oops{
+		[]byte("was killed"),
+		[]oopsFormat{
+			{
+				title: akarosProcKilledRe,
+				fmt:   "%[1]v: %[2]v",
+				stack: &stackFmt{
+					parts: []*regexp.Regexp{
+						akarosBacktraceRe,
+						parseStackTrace,
+					},
+				},
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		[]byte("unhandled user trap"),
+		[]oopsFormat{
+			{
+				title: akarosUserTrapRe,
+				fmt:   "unhandled user trap in %[1]v: %[2]v",
+				stack: &stackFmt{
+					parts: []*regexp.Regexp{
+						akarosBacktraceRe,
+						parseStackTrace,
+					},
+				},
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		[]byte("#PF in user context"),
+		[]oopsFormat{
+			{
+				title: akarosUserPageFaultRe,
+				fmt:   "user page fault in %[1]v",
+				stack: &stackFmt{
+					parts: []*regexp.Regexp{
+						akarosBacktraceRe,
+						parseStackTrace,
+					},
+				},
+			},
+		},
+		[]*regexp.Regexp{},
+	},
 }
@@ -0,0 +1,74 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestUniqueStrings(t *testing.T) {
+	res := uniqueStrings([]string{"a@x.org", "b@x.org", "a@x.org", "", "b@x.org"})
+	if !reflect.DeepEqual(res, []string{"a@x.org", "b@x.org"}) {
+		t.Fatalf("got %v", res)
+	}
+}
+
+func TestParseMaintainersFile(t *testing.T) {
+	data := []byte(`
+M:	Barret Rhoden <brho@cs.berkeley.edu>
+L:	akaros@googlegroups.com
+S:	Maintained
+`)
+	res := parseMaintainersFile(data)
+	if !reflect.DeepEqual(res, []string{"brho@cs.berkeley.edu"}) {
+		t.Fatalf("got %v", res)
+	}
+}
+
+func TestGetMaintainersFromFile(t *testing.T) {
+	kernelSrc, err := ioutil.TempDir("", "syz-akaros-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(kernelSrc)
+	dir := filepath.Join(kernelSrc, "kern", "src")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	maintainers := []byte("M:\tSomeone <someone@example.com>\n")
+	if err := ioutil.WriteFile(filepath.Join(kernelSrc, "kern", "MAINTAINERS"), maintainers, 0600); err != nil {
+		t.Fatal(err)
+	}
+	ctx := &akaros{kernelSrc: kernelSrc}
+	res := ctx.maintainersFromFile("kern/src/monitor.c")
+	if !reflect.DeepEqual(res, []string{"someone@example.com"}) {
+		t.Fatalf("got %v", res)
+	}
+}
+
+func TestGetMaintainersFromFileStopsAtKernelSrc(t *testing.T) {
+	parent, err := ioutil.TempDir("", "syz-akaros-test-parent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(parent)
+	outside := []byte("M:\tOutsider <outsider@example.com>\n")
+	if err := ioutil.WriteFile(filepath.Join(parent, "MAINTAINERS"), outside, 0600); err != nil {
+		t.Fatal(err)
+	}
+	kernelSrc := filepath.Join(parent, "akaros")
+	dir := filepath.Join(kernelSrc, "kern", "src")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	ctx := &akaros{kernelSrc: kernelSrc}
+	res := ctx.maintainersFromFile("kern/src/monitor.c")
+	if res != nil {
+		t.Fatalf("got %v, want nil: a MAINTAINERS file outside kernelSrc must not be picked up", res)
+	}
+}
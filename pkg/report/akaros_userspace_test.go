@@ -0,0 +1,110 @@
+// Copyright 2018 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestAkarosBacktraceRe(t *testing.T) {
+	tests := []struct {
+		line string
+		proc string
+	}{
+		{"Stack Backtrace on Core 0:", ""},
+		{"Backtrace of kernel context on Core 1:", ""},
+		{"Stack Backtrace for process ash on Core 2:", "ash"},
+	}
+	for _, test := range tests {
+		match := akarosBacktraceRe.FindStringSubmatch(test.line)
+		if match == nil {
+			t.Fatalf("%q did not match akarosBacktraceRe", test.line)
+		}
+		if match[1] != test.proc {
+			t.Fatalf("%q: got proc %q, want %q", test.line, match[1], test.proc)
+		}
+	}
+}
+
+func TestAkarosFindBinary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syz-akaros-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "ash"), nil, 0700); err != nil {
+		t.Fatal(err)
+	}
+	ctx := &akaros{objfile: "akaros-kernel-64b", userBinDirs: []string{dir}}
+	if got := ctx.findBinary(""); got != ctx.objfile {
+		t.Fatalf("empty proc: got %v, want kernel objfile", got)
+	}
+	if got := ctx.findBinary("ash"); got != filepath.Join(dir, "ash") {
+		t.Fatalf("known proc: got %v", got)
+	}
+	if got := ctx.findBinary("busybox"); got != ctx.objfile {
+		t.Fatalf("unknown proc: got %v, want kernel objfile", got)
+	}
+}
+
+func TestAkarosFindBinaryKFSLayout(t *testing.T) {
+	kernelObj, err := ioutil.TempDir("", "syz-akaros-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(kernelObj)
+	kfsBin := filepath.Join(kernelObj, "kern", "kfs", "bin")
+	if err := os.MkdirAll(kfsBin, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(kfsBin, "ash"), nil, 0700); err != nil {
+		t.Fatal(err)
+	}
+	testsDir := filepath.Join(kernelObj, "tests")
+	if err := os.MkdirAll(testsDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(testsDir, "syz-executor"), nil, 0700); err != nil {
+		t.Fatal(err)
+	}
+	reporter, _, err := ctorAkaros("", kernelObj, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := reporter.(*akaros)
+	if got, want := ctx.findBinary("ash"), filepath.Join(kfsBin, "ash"); got != want {
+		t.Fatalf("kfs binary: got %v, want %v", got, want)
+	}
+	if got, want := ctx.findBinary("syz-executor"), filepath.Join(testsDir, "syz-executor"); got != want {
+		t.Fatalf("tests binary: got %v, want %v", got, want)
+	}
+	if got := ctx.findBinary("nonexistent"); got != ctx.objfile {
+		t.Fatalf("unknown proc: got %v, want kernel objfile", got)
+	}
+}
+
+func TestAkarosUserOopsTitles(t *testing.T) {
+	tests := []struct {
+		re   *regexp.Regexp
+		line string
+		proc string
+	}{
+		{akarosProcKilledRe, "proc ash (pid 5) was killed due to page fault", "ash"},
+		{akarosUserTrapRe, "unhandled user trap 14 for process busybox (pid 7): #PF", "busybox"},
+		{akarosUserPageFaultRe, "#PF in user context at 0xdeadbeef, from process ash (pid 5)", "ash"},
+	}
+	for _, test := range tests {
+		match := test.re.FindStringSubmatch(test.line)
+		if match == nil {
+			t.Fatalf("%q did not match its oops title regexp", test.line)
+		}
+		if match[1] != test.proc {
+			t.Fatalf("%q: got proc %q, want %q", test.line, match[1], test.proc)
+		}
+	}
+}